@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	quic "github.com/quic-go/quic-go"
+)
+
+var datagramMode = flag.Bool("datagram", false, "Send/receive RFC 9221 unreliable datagrams instead of streams, reporting PPS/loss/jitter")
+
+// datagramHeaderSize is the sequence number + send-timestamp prefix every
+// datagram carries so the receiver can compute loss, reorder distance and
+// round-trip time once the server echoes it back.
+const datagramHeaderSize = 8 + 8 // uint64 seq + int64 unix nanos
+
+// fallbackMaxDatagramSize is used when the connection doesn't expose its
+// negotiated max datagram frame size: a conservative payload budget that
+// fits under the smallest QUIC datagrams are required to support without
+// fragmenting (RFC 9221 §3), comparable to the MaxDatagramSize the
+// congestion package assumes.
+const fallbackMaxDatagramSize = 1200
+
+// datagramSizer is the capability quic.Connection needs to expose to report
+// its actual negotiated max datagram frame size; not all quic-go builds
+// surface it on the stable interface, so we feature-detect it with a type
+// assertion and fall back to fallbackMaxDatagramSize when it is absent.
+type datagramSizer interface {
+	MaxDatagramSize() int
+}
+
+func maxDatagramSize(sess quic.Connection) int {
+	if s, ok := any(sess).(datagramSizer); ok {
+		if max := s.MaxDatagramSize(); max > 0 {
+			return max
+		}
+	}
+	return fallbackMaxDatagramSize
+}
+
+// runDatagramMode negotiates QUIC DATAGRAM support and drives an unreliable
+// throughput test: it sends -n datagrams of -d bytes while a reader
+// goroutine drains incoming datagrams and tracks loss, reorder and jitter
+// from the embedded sequence numbers.
+func runDatagramMode(addr string, tlsConf *tls.Config, quicC *quic.Config) {
+	quicC.EnableDatagrams = true
+
+	sess, err := quic.DialAddr(context.Background(), addr, tlsConf, quicC)
+	if err != nil {
+		fmt.Println("Error connecting to QUIC server:", err)
+		return
+	}
+	fmt.Println("Connected to server:", sess.RemoteAddr())
+
+	maxSize := maxDatagramSize(sess)
+	size := *dataSize
+	if size < datagramHeaderSize {
+		size = datagramHeaderSize
+	}
+	if size > maxSize {
+		fmt.Printf("Requested -d %d exceeds the path's max datagram size (%d); truncating\n", *dataSize, maxSize)
+		size = maxSize
+	}
+
+	var wg sync.WaitGroup
+	recv := newDatagramReceiver()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		recv.run(sess)
+	}()
+
+	fmt.Printf("Datagram test: %d datagrams × %d bytes\n", *numberStream, size)
+	sendStart := time.Now()
+	sent := 0
+	for seq := 0; seq < *numberStream; seq++ {
+		payload := make([]byte, size)
+		binary.BigEndian.PutUint64(payload[0:8], uint64(seq))
+		binary.BigEndian.PutUint64(payload[8:16], uint64(time.Now().UnixNano()))
+		if err := sess.SendMessage(payload); err != nil {
+			fmt.Println("Datagram send error:", err)
+			continue
+		}
+		sent++
+	}
+	sendDur := time.Since(sendStart)
+
+	// Give in-flight datagrams a chance to arrive before we stop the reader.
+	time.Sleep(*readTO)
+	recv.stop()
+	wg.Wait()
+
+	recv.report(sent, sendDur)
+}
+
+// datagramReceiver accumulates stats about inbound unreliable datagrams.
+type datagramReceiver struct {
+	mu               sync.Mutex
+	seqsSeen         []uint64
+	rtts             []time.Duration
+	highest          uint64
+	reordered        int
+	reorderDistances []uint64 // highest-seq for each out-of-order arrival
+	done             chan struct{}
+}
+
+func newDatagramReceiver() *datagramReceiver {
+	return &datagramReceiver{done: make(chan struct{})}
+}
+
+func (r *datagramReceiver) stop() { close(r.done) }
+
+func (r *datagramReceiver) run(sess quic.Connection) {
+	for {
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		payload, err := sess.ReceiveMessage(ctx)
+		cancel()
+		if err != nil {
+			select {
+			case <-r.done:
+				return
+			default:
+				continue
+			}
+		}
+		if len(payload) < datagramHeaderSize {
+			continue
+		}
+
+		seq := binary.BigEndian.Uint64(payload[0:8])
+		sentAt := int64(binary.BigEndian.Uint64(payload[8:16]))
+		// The server echoes the datagram back, and sentAt was stamped by
+		// this same client clock, so this is round-trip time, not a
+		// one-way delay (that would need synchronized clocks on both
+		// ends).
+		rtt := time.Since(time.Unix(0, sentAt))
+
+		r.mu.Lock()
+		if seq < r.highest {
+			r.reordered++
+			r.reorderDistances = append(r.reorderDistances, r.highest-seq)
+		} else {
+			r.highest = seq
+		}
+		r.seqsSeen = append(r.seqsSeen, seq)
+		r.rtts = append(r.rtts, rtt)
+		r.mu.Unlock()
+	}
+}
+
+func (r *datagramReceiver) report(sent int, sendDur time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	received := len(r.seqsSeen)
+	var lossRatio float64
+	if sent > 0 {
+		lossRatio = 1 - float64(received)/float64(sent)
+	}
+
+	pps := float64(0)
+	if sendDur > 0 {
+		pps = float64(sent) / sendDur.Seconds()
+	}
+
+	fmt.Println("Datagram test complete.")
+	fmt.Printf("  Sent: %d, Received: %d, Loss: %.2f%%\n", sent, received, lossRatio*100)
+	fmt.Printf("  Send rate: %.1f pps\n", pps)
+	fmt.Printf("  Reordered: %d, avg reorder distance: %.1f\n", r.reordered, avgDistance(r.reorderDistances))
+	fmt.Printf("  Jitter (stddev of RTT): %s\n", jitter(r.rtts))
+}
+
+// avgDistance is the mean of a set of reorder distances (how many sequence
+// numbers behind the highest seen a reordered datagram arrived).
+func avgDistance(distances []uint64) float64 {
+	if len(distances) == 0 {
+		return 0
+	}
+	var sum uint64
+	for _, d := range distances {
+		sum += d
+	}
+	return float64(sum) / float64(len(distances))
+}
+
+// jitter is the standard deviation of the RTT samples, a common RFC
+// 3550-style jitter approximation.
+func jitter(delays []time.Duration) time.Duration {
+	if len(delays) < 2 {
+		return 0
+	}
+
+	var sum time.Duration
+	for _, d := range delays {
+		sum += d
+	}
+	mean := sum / time.Duration(len(delays))
+
+	var variance float64
+	for _, d := range delays {
+		diff := float64(d - mean)
+		variance += diff * diff
+	}
+	variance /= float64(len(delays))
+	return time.Duration(math.Sqrt(variance))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitterNoSamples(t *testing.T) {
+	if got := jitter(nil); got != 0 {
+		t.Errorf("jitter(nil) = %s, want 0", got)
+	}
+	if got := jitter([]time.Duration{5 * time.Millisecond}); got != 0 {
+		t.Errorf("jitter(single sample) = %s, want 0", got)
+	}
+}
+
+func TestJitterConstantDelayIsZero(t *testing.T) {
+	delays := []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond}
+	if got := jitter(delays); got != 0 {
+		t.Errorf("jitter(constant delays) = %s, want 0", got)
+	}
+}
+
+func TestJitterVaryingDelayIsPositive(t *testing.T) {
+	delays := []time.Duration{5 * time.Millisecond, 50 * time.Millisecond, 5 * time.Millisecond, 50 * time.Millisecond}
+	if got := jitter(delays); got <= 0 {
+		t.Errorf("jitter(varying delays) = %s, want > 0", got)
+	}
+}
+
+func TestMaxDatagramSizeFallsBackWithoutSizer(t *testing.T) {
+	if got := maxDatagramSize(nil); got != fallbackMaxDatagramSize {
+		t.Errorf("maxDatagramSize(nil) = %d, want fallback %d", got, fallbackMaxDatagramSize)
+	}
+}
+
+func TestAvgDistance(t *testing.T) {
+	if got := avgDistance(nil); got != 0 {
+		t.Errorf("avgDistance(nil) = %v, want 0", got)
+	}
+	if got := avgDistance([]uint64{1, 3, 2}); got != 2 {
+		t.Errorf("avgDistance([1,3,2]) = %v, want 2", got)
+	}
+}
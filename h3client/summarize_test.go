@@ -0,0 +1,56 @@
+package h3client
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSummarizeNoResultsAvoidsDivideByZero(t *testing.T) {
+	s := summarize(nil, 5*time.Second)
+	if s.Total != 0 || s.Errors != 0 {
+		t.Fatalf("summarize(nil) = %+v, want zero Total and Errors", s)
+	}
+	if s.AvgTTFB != 0 {
+		t.Errorf("AvgTTFB = %s, want 0", s.AvgTTFB)
+	}
+}
+
+func TestSummarizeCountsStatusCodes(t *testing.T) {
+	results := []Result{
+		{StatusCode: 200, BodyBytes: 10},
+		{StatusCode: 200, BodyBytes: 20},
+		{StatusCode: 404, BodyBytes: 0},
+	}
+	s := summarize(results, time.Second)
+	if s.Total != 3 {
+		t.Fatalf("Total = %d, want 3", s.Total)
+	}
+	if got := s.StatusCounts[200]; got != 2 {
+		t.Errorf("StatusCounts[200] = %d, want 2", got)
+	}
+	if got := s.StatusCounts[404]; got != 1 {
+		t.Errorf("StatusCounts[404] = %d, want 1", got)
+	}
+	if s.TotalBodyBytes != 30 {
+		t.Errorf("TotalBodyBytes = %d, want 30", s.TotalBodyBytes)
+	}
+}
+
+func TestSummarizeExcludesErrorsFromAvgTTFB(t *testing.T) {
+	results := []Result{
+		{StatusCode: 200, TTFB: 10 * time.Millisecond},
+		{StatusCode: 200, TTFB: 30 * time.Millisecond},
+		{Err: errors.New("dial failed"), TTFB: 900 * time.Millisecond},
+	}
+	s := summarize(results, time.Second)
+	if s.Errors != 1 {
+		t.Fatalf("Errors = %d, want 1", s.Errors)
+	}
+	if want := 20 * time.Millisecond; s.AvgTTFB != want {
+		t.Errorf("AvgTTFB = %s, want %s (errored request's TTFB must not count)", s.AvgTTFB, want)
+	}
+	if _, ok := s.StatusCounts[0]; ok {
+		t.Errorf("StatusCounts has an entry for the errored request's zero-value status code")
+	}
+}
@@ -0,0 +1,55 @@
+package congestion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBBRStartsInStartupWithInitialWindow(t *testing.T) {
+	b := newBBRSender()
+	if b.mode != bbrStartup {
+		t.Fatalf("new sender mode = %v, want bbrStartup", b.mode)
+	}
+	if got := b.GetCongestionWindow(); got != initialCongestionWindow {
+		t.Errorf("GetCongestionWindow() = %d, want %d", got, initialCongestionWindow)
+	}
+}
+
+func TestBBREntersDrainAfterBandwidthPlateaus(t *testing.T) {
+	b := newBBRSender()
+	now := time.Now()
+	// Feed a flat bandwidth signal for enough rounds to trip
+	// checkStartupDone's 3-consecutive-non-growth counter.
+	for i := 0; i < 6; i++ {
+		now = now.Add(50 * time.Millisecond)
+		b.OnPacketSent(now, 0, int64(i), MaxDatagramSize, true)
+		now = now.Add(50 * time.Millisecond)
+		b.OnPacketAcked(int64(i), MaxDatagramSize, 0, now)
+	}
+	if b.mode != bbrDrain && b.mode != bbrProbeBW {
+		t.Errorf("mode after a long flat-bandwidth run = %v, want Drain or ProbeBW", b.mode)
+	}
+}
+
+func TestBBRLossClampsWindowToTarget(t *testing.T) {
+	b := newBBRSender()
+	b.cwnd = b.targetCwnd() + 10*MaxDatagramSize
+	b.OnPacketLost(1, MaxDatagramSize, 0)
+	if b.cwnd > b.targetCwnd() {
+		t.Errorf("cwnd not clamped to target after loss: cwnd=%d target=%d", b.cwnd, b.targetCwnd())
+	}
+}
+
+func TestModeNameCoversAllModes(t *testing.T) {
+	cases := map[bbrMode]string{
+		bbrStartup:  "startup",
+		bbrDrain:    "drain",
+		bbrProbeBW:  "probe_bw",
+		bbrProbeRTT: "probe_rtt",
+	}
+	for mode, want := range cases {
+		if got := modeName(mode); got != want {
+			t.Errorf("modeName(%v) = %q, want %q", mode, got, want)
+		}
+	}
+}
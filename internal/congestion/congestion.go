@@ -0,0 +1,54 @@
+// Package congestion implements pluggable congestion controllers (CUBIC,
+// NewReno and BBR) selected via -cc. Stock quic-go doesn't expose a
+// connection-level congestion control hook, so these don't drive the QUIC
+// stack's own loss recovery/cwnd; main.go instead uses TimeUntilSend to pace
+// its own upload-stream writes at the application level.
+package congestion
+
+import "time"
+
+const (
+	// MaxDatagramSize is the assumed QUIC packet size used for all cwnd
+	// math below (bytes).
+	MaxDatagramSize = 1252
+
+	minCongestionWindowPackets = 2
+	initialCongestionWindow    = 32 * MaxDatagramSize
+)
+
+// Controller is the interface every congestion controller implements. Its
+// shape mirrors quic-go's internal congestion.SendAlgorithm, but since that
+// hook isn't exposed on the public API, main.go drives these manually from
+// the upload loop instead of installing one on the connection.
+type Controller interface {
+	// OnPacketSent records that a packet of size bytes was sent at t and
+	// is in flight.
+	OnPacketSent(t time.Time, bytesInFlight, packetNumber, bytes int64, isRetransmittable bool)
+	// OnPacketAcked records that a packet was acknowledged.
+	OnPacketAcked(packetNumber int64, ackedBytes int64, priorInFlight int64, t time.Time)
+	// OnPacketLost records a packet loss.
+	OnPacketLost(packetNumber int64, lostBytes int64, priorInFlight int64)
+	// GetCongestionWindow returns the current congestion window in bytes.
+	GetCongestionWindow() int64
+	// TimeUntilSend returns how long to wait before the next packet may
+	// be sent, for pacing. Zero means "send now".
+	TimeUntilSend(bytesInFlight int64) time.Duration
+	// Name identifies the controller for reporting (e.g. "cubic", "bbr").
+	Name() string
+}
+
+// New builds a Controller for the given name ("cubic", "newreno" or "bbr").
+// It returns (nil, false) for unknown names so callers can fall back to the
+// library default.
+func New(name string) (Controller, bool) {
+	switch name {
+	case "cubic":
+		return newCubicSender(false), true
+	case "newreno":
+		return newCubicSender(true), true
+	case "bbr":
+		return newBBRSender(), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,127 @@
+package congestion
+
+import (
+	"math"
+	"time"
+)
+
+// cubicSender implements both CUBIC and NewReno, selected by the reno flag,
+// since the two share their slow-start logic and differ only in how the
+// congestion avoidance window grows and in how far a loss cuts the window
+// (to half for NewReno, to cubicBeta for CUBIC).
+type cubicSender struct {
+	reno bool
+
+	cwnd               int64
+	slowStartThreshold int64
+
+	// CUBIC state.
+	originPoint    float64
+	lastMaxCwnd    float64
+	epochStart     time.Time
+	kTime          float64
+	ackedSinceLoss int64
+
+	lastLossTime time.Time
+}
+
+const (
+	cubicC    = 0.4
+	cubicBeta = 0.7
+)
+
+func newCubicSender(reno bool) *cubicSender {
+	return &cubicSender{
+		reno:               reno,
+		cwnd:               initialCongestionWindow,
+		slowStartThreshold: math.MaxInt64,
+	}
+}
+
+func (c *cubicSender) Name() string {
+	if c.reno {
+		return "newreno"
+	}
+	return "cubic"
+}
+
+func (c *cubicSender) OnPacketSent(t time.Time, bytesInFlight, packetNumber, bytes int64, isRetransmittable bool) {
+	// Nothing to track beyond bytesInFlight, which the caller already
+	// maintains; CUBIC/NewReno only react to acks and losses.
+}
+
+func (c *cubicSender) inSlowStart() bool {
+	return c.cwnd < c.slowStartThreshold
+}
+
+func (c *cubicSender) OnPacketAcked(packetNumber int64, ackedBytes int64, priorInFlight int64, t time.Time) {
+	c.ackedSinceLoss += ackedBytes
+
+	if c.inSlowStart() {
+		c.cwnd += ackedBytes
+		return
+	}
+
+	if c.reno {
+		// Classic additive increase: one MSS per RTT, approximated per ack.
+		c.cwnd += MaxDatagramSize * ackedBytes / c.cwnd
+		return
+	}
+
+	c.cwnd = int64(c.cubicWindow(t))
+}
+
+// cubicWindow implements the CUBIC window growth function
+// W(t) = C*(t-K)^3 + Wmax.
+func (c *cubicSender) cubicWindow(t time.Time) float64 {
+	if c.epochStart.IsZero() {
+		c.epochStart = t
+		c.originPoint = float64(c.cwnd)
+		if c.lastMaxCwnd <= float64(c.cwnd) {
+			c.kTime = 0
+		} else {
+			c.kTime = math.Cbrt((c.lastMaxCwnd - float64(c.cwnd)) / cubicC)
+		}
+	}
+
+	elapsed := t.Sub(c.epochStart).Seconds()
+	target := cubicC*math.Pow(elapsed-c.kTime, 3) + c.lastMaxCwnd
+	if target < float64(c.cwnd) {
+		// Still recovering; grow slowly towards origin + small increment.
+		target = c.originPoint + float64(MaxDatagramSize)*elapsed
+	}
+	return target
+}
+
+func (c *cubicSender) OnPacketLost(packetNumber int64, lostBytes int64, priorInFlight int64) {
+	c.lastLossTime = time.Now()
+	c.epochStart = time.Time{}
+
+	if c.reno {
+		c.cwnd = maxInt64(int64(float64(c.cwnd)*0.5), minCongestionWindowPackets*MaxDatagramSize)
+		c.slowStartThreshold = c.cwnd
+		return
+	}
+
+	c.lastMaxCwnd = float64(c.cwnd)
+	c.cwnd = maxInt64(int64(float64(c.cwnd)*cubicBeta), minCongestionWindowPackets*MaxDatagramSize)
+	c.slowStartThreshold = c.cwnd
+}
+
+func (c *cubicSender) GetCongestionWindow() int64 {
+	return c.cwnd
+}
+
+func (c *cubicSender) TimeUntilSend(bytesInFlight int64) time.Duration {
+	if bytesInFlight < c.GetCongestionWindow() {
+		return 0
+	}
+	return time.Millisecond
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,57 @@
+package congestion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCubicSlowStartGrowsOnAck(t *testing.T) {
+	c := newCubicSender(false)
+	start := c.GetCongestionWindow()
+	c.OnPacketAcked(1, MaxDatagramSize, 0, time.Now())
+	if got := c.GetCongestionWindow(); got <= start {
+		t.Fatalf("cwnd did not grow in slow start: got %d, started at %d", got, start)
+	}
+}
+
+func TestCubicLossShrinksWindowAndExitsSlowStart(t *testing.T) {
+	c := newCubicSender(false)
+	before := c.GetCongestionWindow()
+	c.OnPacketLost(1, MaxDatagramSize, 0)
+	after := c.GetCongestionWindow()
+	if after >= before {
+		t.Fatalf("cwnd did not shrink on loss: before=%d after=%d", before, after)
+	}
+	if c.inSlowStart() {
+		t.Fatal("still in slow start after a loss set slowStartThreshold")
+	}
+}
+
+func TestNewRenoAdditiveIncreaseAfterSlowStart(t *testing.T) {
+	c := newCubicSender(true)
+	c.OnPacketLost(1, MaxDatagramSize, 0) // drop out of slow start
+	before := c.GetCongestionWindow()
+	c.OnPacketAcked(2, MaxDatagramSize, 0, time.Now())
+	if got := c.GetCongestionWindow(); got <= before {
+		t.Fatalf("newreno congestion avoidance did not increase cwnd: before=%d after=%d", before, got)
+	}
+}
+
+func TestCubicNameReflectsReno(t *testing.T) {
+	if got := newCubicSender(false).Name(); got != "cubic" {
+		t.Errorf("Name() = %q, want %q", got, "cubic")
+	}
+	if got := newCubicSender(true).Name(); got != "newreno" {
+		t.Errorf("Name() = %q, want %q", got, "newreno")
+	}
+}
+
+func TestTimeUntilSendGatesOnCongestionWindow(t *testing.T) {
+	c := newCubicSender(false)
+	if wait := c.TimeUntilSend(0); wait != 0 {
+		t.Errorf("expected no wait below cwnd, got %s", wait)
+	}
+	if wait := c.TimeUntilSend(c.GetCongestionWindow()); wait <= 0 {
+		t.Errorf("expected a positive wait at/above cwnd, got %s", wait)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	quic "github.com/quic-go/quic-go"
+)
+
+// Test case names recognized via TESTCASE, matching the naming the QUIC
+// interop runner (https://github.com/quic-interop/quic-interop-runner) uses
+// to select a client flow.
+const (
+	testcaseHandshake    = "handshake"
+	testcaseTransfer     = "transfer"
+	testcaseRetry        = "retry"
+	testcaseResumption   = "resumption"
+	testcaseZeroRTT      = "zerortt"
+	testcaseMultiConnect = "multiconnect"
+	testcaseKeyUpdate    = "keyupdate"
+	testcaseV2           = "v2"
+	testcaseChaCha20     = "chacha20"
+	testcaseHTTP3        = "http3"
+)
+
+var outputDir = flag.String("output", "/downloads", "Directory interop test cases write downloaded files into")
+
+// runInteropMode dispatches on TESTCASE and turns this binary into a
+// drop-in interop client image. It calls os.Exit itself: 127 for an
+// unsupported test case (as the runner expects) or 1 on a hard failure of
+// a supported one.
+func runInteropMode(testcase, addr string, tlsConf *tls.Config, quicC *quic.Config) {
+	urls := flag.Args()
+
+	switch testcase {
+	case testcaseHandshake, testcaseRetry:
+		// Retry is handled transparently by quic-go during a normal dial,
+		// so a successful handshake is the entire test.
+		runHandshakeOnly(addr, tlsConf, quicC)
+
+	case testcaseV2:
+		v2Config := *quicC
+		v2Config.Versions = []quic.VersionNumber{quic.Version2}
+		runHandshakeOnly(addr, tlsConf, &v2Config)
+
+	case testcaseChaCha20:
+		chachaTLS := tlsConf.Clone()
+		chachaTLS.CipherSuites = []uint16{tls.TLS_CHACHA20_POLY1305_SHA256}
+		runHandshakeOnly(addr, chachaTLS, quicC)
+
+	case testcaseTransfer:
+		runTransfer(urls, tlsConf, quicC)
+
+	case testcaseResumption:
+		runResumption(addr, urls, tlsConf, quicC, false)
+
+	case testcaseZeroRTT:
+		runResumption(addr, urls, tlsConf, quicC, true)
+
+	case testcaseMultiConnect:
+		runMultiConnect(urls, tlsConf, quicC)
+
+	case testcaseKeyUpdate:
+		runKeyUpdate(addr, tlsConf, quicC)
+
+	case testcaseHTTP3:
+		runH3Test(urls, tlsConf, quicC)
+
+	default:
+		fmt.Println("Unsupported TESTCASE:", testcase)
+		os.Exit(127)
+	}
+}
+
+func runHandshakeOnly(addr string, tlsConf *tls.Config, quicC *quic.Config) {
+	sess, err := quic.DialAddr(context.Background(), addr, tlsConf, quicC)
+	if err != nil {
+		fmt.Println("handshake failed:", err)
+		os.Exit(1)
+	}
+	fmt.Println("handshake ok:", sess.RemoteAddr())
+	_ = sess.CloseWithError(0, "")
+}
+
+func runTransfer(urls []string, tlsConf *tls.Config, quicC *quic.Config) {
+	if len(urls) == 0 {
+		fmt.Println("transfer: no URLs given on argv")
+		os.Exit(1)
+	}
+	if err := downloadURLs(urls, *outputDir, tlsConf, quicC); err != nil {
+		fmt.Println("transfer failed:", err)
+		os.Exit(1)
+	}
+}
+
+func runH3Test(urls []string, tlsConf *tls.Config, quicC *quic.Config) {
+	if err := runH3Mode(urls, tlsConf, quicC); err != nil {
+		fmt.Println("http3:", err)
+		os.Exit(1)
+	}
+}
+
+func runMultiConnect(urls []string, tlsConf *tls.Config, quicC *quic.Config) {
+	if len(urls) == 0 {
+		fmt.Println("multiconnect: no URLs given on argv")
+		os.Exit(1)
+	}
+	for i, u := range urls {
+		addr, err := addrFromURL(u)
+		if err != nil {
+			fmt.Println("multiconnect: bad URL", u, err)
+			os.Exit(1)
+		}
+		sess, err := quic.DialAddr(context.Background(), addr, tlsConf.Clone(), quicC)
+		if err != nil {
+			fmt.Printf("multiconnect: dial %d (%s) failed: %v\n", i, addr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("multiconnect: connection %d to %s ok\n", i, addr)
+		_ = sess.CloseWithError(0, "")
+	}
+	if err := downloadURLs(urls, *outputDir, tlsConf, quicC); err != nil {
+		fmt.Println("multiconnect: download failed:", err)
+		os.Exit(1)
+	}
+}
+
+// runKeyUpdate dials and then idles. quic-go has no public API for an
+// application to trigger a key update itself, but it handles both
+// self-initiated and peer-initiated key updates transparently once a packet
+// threshold is reached - so this case just needs to keep the connection
+// open long enough for the runner's server to drive one and confirm the
+// client is still talking afterwards.
+func runKeyUpdate(addr string, tlsConf *tls.Config, quicC *quic.Config) {
+	sess, err := quic.DialAddr(context.Background(), addr, tlsConf, quicC)
+	if err != nil {
+		fmt.Println("keyupdate: dial failed:", err)
+		os.Exit(1)
+	}
+	defer sess.CloseWithError(0, "")
+
+	fmt.Println("keyupdate: connection established, idling to allow a key update:", sess.RemoteAddr())
+	time.Sleep(3 * time.Second)
+	fmt.Println("keyupdate: still connected after idle period")
+}
+
+func runResumption(addr string, urls []string, tlsConf *tls.Config, quicC *quic.Config, zeroRTT bool) {
+	cachePath := filepath.Join(*outputDir, "interop_session_cache.bin")
+	cache := newDiskSessionCache(cachePath)
+	tlsConf = tlsConf.Clone()
+	tlsConf.ClientSessionCache = cache
+
+	var sess quic.Connection
+	var err error
+	if zeroRTT {
+		sess, err = quic.DialAddrEarly(context.Background(), addr, tlsConf, quicC)
+	} else {
+		sess, err = quic.DialAddr(context.Background(), addr, tlsConf, quicC)
+	}
+	if err != nil {
+		fmt.Println("resumption: dial failed:", err)
+		os.Exit(1)
+	}
+	defer sess.CloseWithError(0, "")
+
+	if zeroRTT {
+		used := sess.ConnectionState().Used0RTT
+		fmt.Println("resumption: 0-RTT used:", used)
+	}
+
+	if len(urls) > 0 {
+		if err := downloadURLs(urls, *outputDir, tlsConf, quicC); err != nil {
+			fmt.Println("resumption: download failed:", err)
+			os.Exit(1)
+		}
+	}
+
+	// The server's post-handshake NewSessionTicket (the one that actually
+	// enables 0-RTT next time) typically arrives while data is in flight,
+	// not immediately after the handshake - so save the cache only after
+	// the transfer has had a chance to receive it.
+	cache.save()
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"crypto/tls"
 	"flag"
@@ -9,13 +8,15 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
 	quic "github.com/quic-go/quic-go"
-	"github.com/quic-go/quic-go/logging"
-	"github.com/quic-go/quic-go/qlog"
+
+	"github.com/EMESSARL/quic-client-linux/h3client"
+	"github.com/EMESSARL/quic-client-linux/internal/congestion"
 )
 
 const ratio = 1048576
@@ -28,6 +29,19 @@ var (
 	downloadUni  = flag.Bool("uni", false, "Expect unidirectional streams for the download phase (server-initiated)")
 	readTO       = flag.Duration("read-timeout", 30*time.Second, "Per-read timeout during download phase")
 	idleTO       = flag.Duration("idle-timeout", 45*time.Second, "QUIC connection idle timeout")
+
+	mode       = flag.String("mode", "stream", "Test mode: 'stream' (raw QUIC echo) or 'h3' (HTTP/3 requests)")
+	h3URLs     = flag.String("urls", "", "Comma-separated list of URLs for -mode h3")
+	h3Method   = flag.String("method", "GET", "HTTP method to use for -mode h3")
+	h3BodySize = flag.Int("body-size", 0, "Request body size (bytes) for -mode h3 POST/upload tests")
+	h3Headers  = flag.String("header", "", "Comma-separated list of key:value headers for -mode h3")
+	h3Conc     = flag.Int("h3-concurrency", 10, "Number of concurrent requests per URL for -mode h3")
+
+	ccAlgo = flag.String("cc", "", "Pace this client's own upload writes using cubic, newreno or bbr (application-level pacing only, does not replace quic-go's connection congestion control; default: no pacing)")
+
+	qlogDir        = flag.String("qlog-dir", "qlogs", "Directory for qlog NDJSON segments")
+	qlogLevel      = flag.String("qlog-level", "all", "qlog event categories to keep: core, recovery, frame or all")
+	qlogRotateSize = flag.Int64("qlog-rotate-size", 64*1024*1024, "Rotate (and gzip) a qlog segment once it reaches this many bytes, 0 disables rotation")
 )
 
 var mu sync.Mutex
@@ -43,23 +57,6 @@ func generatePRData(l int) []byte {
 	return res
 }
 
-// Buffered writer
-type bufferedWriteCloser struct {
-	*bufio.Writer
-	io.Closer
-}
-
-func NewBufferedWriteCloser(writer *bufio.Writer, closer io.Closer) io.WriteCloser {
-	return &bufferedWriteCloser{Writer: writer, Closer: closer}
-}
-
-func (h bufferedWriteCloser) Close() error {
-	if err := h.Writer.Flush(); err != nil {
-		return err
-	}
-	return h.Closer.Close()
-}
-
 func main() {
 	flag.Parse()
 	addr := *url + ":" + strconv.Itoa(*port)
@@ -80,14 +77,36 @@ func main() {
 	quicC := &quic.Config{
 		MaxIncomingStreams: 150,
 		MaxIdleTimeout:     *idleTO,
-		Tracer: func(ctx context.Context, p logging.Perspective, connID quic.ConnectionID) *logging.ConnectionTracer {
-			_ = os.MkdirAll("qlogs", 0755)
-			filename := fmt.Sprintf("qlogs/client_%s.qlog", time.Now().Format("2006-01-02_15-04-05"))
-			f, _ := os.Create(filename)
-			fmt.Printf("Creating client qlog: %s\n", filename)
-			w := NewBufferedWriteCloser(bufio.NewWriter(f), f)
-			return qlog.NewConnectionTracer(w, p, connID)
-		},
+		Tracer:             newQlogTracer(*qlogDir, *qlogLevel, *qlogRotateSize),
+	}
+
+	if tc := os.Getenv("TESTCASE"); tc != "" {
+		runInteropMode(tc, addr, tlsConf, quicC)
+		return
+	}
+
+	if *mode == "h3" {
+		var urls []string
+		if *h3URLs != "" {
+			urls = strings.Split(*h3URLs, ",")
+			for i := range urls {
+				urls[i] = strings.TrimSpace(urls[i])
+			}
+		}
+		if err := runH3Mode(urls, tlsConf, quicC); err != nil {
+			fmt.Println("Error:", err)
+		}
+		return
+	}
+
+	if *datagramMode {
+		runDatagramMode(addr, tlsConf, quicC)
+		return
+	}
+
+	if *sessionFile != "" {
+		runSessionResumeMode(addr, tlsConf, quicC)
+		return
 	}
 
 	fmt.Println("Starting QUIC test...")
@@ -98,6 +117,17 @@ func main() {
 	}
 	fmt.Println("Connected to server:", sess.RemoteAddr())
 
+	var cc congestion.Controller
+	if *ccAlgo != "" {
+		var ok bool
+		cc, ok = congestion.New(*ccAlgo)
+		if !ok {
+			fmt.Println("Unknown -cc value:", *ccAlgo)
+		} else {
+			fmt.Println("Pacing uploads with congestion controller:", cc.Name())
+		}
+	}
+
 	// ---------------- Upload Test ----------------
 	spin := spinner.New(spinner.CharSets[43], 100*time.Millisecond)
 	msg := generatePRData(*dataSize)
@@ -120,16 +150,26 @@ func main() {
 			_ = stream.SetWriteDeadline(time.Now().Add(30 * time.Second))
 			remaining := len(msg)
 			off := 0
+			var pktNum int64
 			for remaining > 0 {
 				chunk := 64 * 1024
 				if remaining < chunk {
 					chunk = remaining
 				}
+
+				paceUpload(cc)
+
 				n, err := stream.Write(msg[off : off+chunk])
 				if err != nil {
 					fmt.Println("Stream write error:", err)
 					return
 				}
+
+				if cc != nil {
+					recordUploadSent(cc, pktNum, int64(n))
+					pktNum++
+				}
+
 				off += n
 				remaining -= n
 
@@ -255,5 +295,120 @@ func main() {
 
 	fmt.Printf("Received total: %d bytes across %d streams\n", total, len(times))
 	fmt.Printf("Download Speed (avg): %.3f Mbps\n", Mbps)
+	if cc != nil {
+		printCongestionSummary(cc)
+	}
 	fmt.Println("Test finished successfully.")
 }
+
+// ccMu guards ccInFlight and every call into the shared congestion.Controller
+// from the concurrent upload-stream goroutines below.
+var ccMu sync.Mutex
+
+// ccInFlight is the number of bytes written to upload streams that haven't
+// been "acked" yet (see ccSimulatedRTT below). Real quic-go ACK feedback
+// isn't available to application code, so this is the closest stand-in for
+// bytes-in-flight that CUBIC/NewReno's window-gated TimeUntilSend needs to
+// have any effect; without it, bytesInFlight would always read back as 0 and
+// -cc cubic/-cc newreno would never pace at all.
+var ccInFlight int64
+
+// ccSimulatedRTT stands in for a real ACK delay: OnPacketAcked fires this
+// long after OnPacketSent, which is what lets ccInFlight actually build up
+// past a small cwnd and makes cubic/newreno's window gating bite. BBR relies
+// on it too: its bandwidth and min-RTT estimates are built from these
+// simulated acks, and its TimeUntilSend also holds writes while
+// bytesInFlight is at or above its cwnd.
+const ccSimulatedRTT = 20 * time.Millisecond
+
+// paceUpload sleeps for cc's pacing delay before the next stream write,
+// approximating the congestion controller's pacing gain at the application
+// level: stock quic-go doesn't expose a connection-level congestion control
+// hook, so -cc can't drive the QUIC stack's own loss recovery/cwnd - it only
+// throttles how fast this client itself writes to its streams.
+func paceUpload(cc congestion.Controller) {
+	if cc == nil {
+		return
+	}
+	ccMu.Lock()
+	wait := cc.TimeUntilSend(ccInFlight)
+	ccMu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// recordUploadSent tells cc a chunk of size n was written, growing ccInFlight
+// immediately and scheduling the simulated ack that will shrink it back down
+// after ccSimulatedRTT.
+func recordUploadSent(cc congestion.Controller, pktNum, n int64) {
+	if cc == nil {
+		return
+	}
+	ccMu.Lock()
+	cc.OnPacketSent(time.Now(), ccInFlight, pktNum, n, true)
+	ccInFlight += n
+	ccMu.Unlock()
+
+	time.AfterFunc(ccSimulatedRTT, func() {
+		ccMu.Lock()
+		defer ccMu.Unlock()
+		cc.OnPacketAcked(pktNum, n, ccInFlight, time.Now())
+		ccInFlight -= n
+		if ccInFlight < 0 {
+			ccInFlight = 0
+		}
+	})
+}
+
+func printCongestionSummary(cc congestion.Controller) {
+	fmt.Printf("Congestion controller: %s, final cwnd: %d bytes\n", cc.Name(), cc.GetCongestionWindow())
+	if bbr, ok := cc.(interface {
+		BandwidthEstimate() float64
+		MinRTT() time.Duration
+	}); ok {
+		// bandwidth/minRTT are derived from ccSimulatedRTT's fixed 20ms ack
+		// delay (see recordUploadSent), not real network feedback - label
+		// them as such so they're never mistaken for a measurement.
+		fmt.Printf("  bandwidth estimate (simulated, not measured from the network): %.0f bytes/s, min RTT (simulated): %s\n", bbr.BandwidthEstimate(), bbr.MinRTT())
+	}
+}
+
+// runH3Mode drives the HTTP/3 benchmarking flow (-mode h3): it issues
+// concurrent GET/POST requests against urls and reports TTFB, body
+// throughput and the status code distribution. Callers pass either the
+// parsed -urls flag (standalone -mode h3) or flag.Args() (TESTCASE=http3);
+// it's their job to decide what an empty/failed run means for their exit
+// code, so errors are returned rather than handled here.
+func runH3Mode(urls []string, tlsConf *tls.Config, quicC *quic.Config) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("-mode h3 requires -urls")
+	}
+
+	headers := map[string]string{}
+	if *h3Headers != "" {
+		for _, kv := range strings.Split(*h3Headers, ",") {
+			parts := strings.SplitN(strings.TrimSpace(kv), ":", 2)
+			if len(parts) == 2 {
+				headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+
+	fmt.Printf("HTTP/3 test: %d URL(s), method=%s, concurrency=%d\n", len(urls), *h3Method, *h3Conc)
+
+	summary, err := h3client.Run(context.Background(), h3client.Config{
+		URLs:        urls,
+		Method:      *h3Method,
+		BodySize:    *h3BodySize,
+		Headers:     headers,
+		Concurrency: *h3Conc,
+		TLSConfig:   tlsConf,
+		QuicConfig:  quicC,
+	})
+	if err != nil {
+		return fmt.Errorf("HTTP/3 test error: %w", err)
+	}
+	h3client.PrintReport(summary)
+	return nil
+}
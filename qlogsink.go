@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	quic "github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/logging"
+	"github.com/quic-go/quic-go/qlog"
+)
+
+// qlogCategory is the coarse event grouping -qlog-level filters on. qlog
+// event names are namespaced as "<category>:<event>" (e.g.
+// "recovery:packet_lost", "transport:packet_sent"), so we bucket on the
+// prefix rather than maintaining an explicit event list.
+type qlogCategory int
+
+const (
+	qlogCore qlogCategory = iota
+	qlogRecovery
+	qlogFrame
+	qlogOther
+)
+
+func categoryOf(eventName string) qlogCategory {
+	prefix, _, _ := strings.Cut(eventName, ":")
+	switch prefix {
+	case "connectivity", "transport", "quic", "security":
+		return qlogCore
+	case "recovery":
+		return qlogRecovery
+	default:
+		if strings.Contains(eventName, "frame") {
+			return qlogFrame
+		}
+		return qlogOther
+	}
+}
+
+// levelAllows reports whether an event of the given category should be kept
+// for the -qlog-level value in effect.
+func levelAllows(level string, cat qlogCategory) bool {
+	switch level {
+	case "core":
+		return cat == qlogCore
+	case "recovery":
+		return cat == qlogCore || cat == qlogRecovery
+	case "frame":
+		return cat == qlogCore || cat == qlogRecovery || cat == qlogFrame
+	case "all", "":
+		return true
+	default:
+		return true
+	}
+}
+
+// manifestEntry describes one qlog segment - a rotated, gzip-compressed one,
+// or the final, uncompressed segment recorded by Close - so that external
+// tools (qvis, etc.) can stitch the stream back together.
+type manifestEntry struct {
+	File       string    `json:"file"`
+	ConnID     string    `json:"connection_id"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	RecordSize int64     `json:"bytes"`
+}
+
+// rotatingQlogWriter is an io.WriteCloser that sits behind
+// qlog.NewConnectionTracer and filters its NDJSON output by category, then,
+// once the current segment crosses rotateAt bytes, gzip-compresses it and
+// opens a new one.
+//
+// quic-go's tracer (qlog.NewConnectionTracer, quic-go@v0.39.4 as pinned in go.mod)
+// never emits a record-separator byte: the leading trace/header record
+// arrives as a single Write containing its JSON and trailing '\n' together,
+// while each event record arrives as a Write of its JSON bytes followed by
+// a separate one-byte '\n' Write. So the only reliable record boundary is
+// "the bytes written so far end in '\n'" - we buffer every Write into
+// pending and flush whenever that holds, regardless of which Write call the
+// newline arrived on. We re-emit each record with a trailing '\n' of our
+// own; we never add any other framing since quic-go's output carries none.
+type rotatingQlogWriter struct {
+	mu sync.Mutex
+
+	dir      string
+	level    string
+	connID   string
+	rotateAt int64
+
+	pending []byte
+
+	f          *os.File
+	w          *bufio.Writer
+	written    int64
+	segStart   time.Time
+	segIdx     int
+	manifest   []manifestEntry
+	manifestFp string
+}
+
+func newRotatingQlogWriter(dir, level, connID string, rotateAt int64) *rotatingQlogWriter {
+	_ = os.MkdirAll(dir, 0755)
+	w := &rotatingQlogWriter{
+		dir:        dir,
+		level:      level,
+		connID:     connID,
+		rotateAt:   rotateAt,
+		manifestFp: filepath.Join(dir, fmt.Sprintf("client_%s.manifest.json", connID)),
+	}
+	w.openSegment()
+	return w
+}
+
+func (w *rotatingQlogWriter) segmentName() string {
+	return filepath.Join(w.dir, fmt.Sprintf("client_%s.%03d.qlog", w.connID, w.segIdx))
+}
+
+func (w *rotatingQlogWriter) openSegment() {
+	name := w.segmentName()
+	f, err := os.Create(name)
+	if err != nil {
+		fmt.Println("qlog: failed to create segment:", err)
+		return
+	}
+	w.f = f
+	w.w = bufio.NewWriter(f)
+	w.written = 0
+	w.segStart = time.Now()
+	fmt.Printf("Creating client qlog segment: %s\n", name)
+}
+
+// Write buffers p into the in-progress record and, once the accumulated
+// bytes end in '\n' (quic-go's only record boundary - see the type doc
+// comment), decides whether to keep the record and flushes it.
+func (w *rotatingQlogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	if len(w.pending) > 0 && w.pending[len(w.pending)-1] == '\n' {
+		if err := w.flushRecord(); err != nil {
+			return 0, err
+		}
+	}
+
+	if w.rotateAt > 0 && w.written >= w.rotateAt {
+		w.rotate()
+	}
+	return len(p), nil
+}
+
+// flushRecord writes the reassembled record (if it passes the -qlog-level
+// filter) with a trailing newline, then resets pending state.
+func (w *rotatingQlogWriter) flushRecord() error {
+	record := w.pending[:len(w.pending)-1] // drop the trailing '\n'
+	w.pending = nil
+
+	if !w.shouldKeep(record) {
+		return nil
+	}
+
+	n, err := w.w.Write(record)
+	if err != nil {
+		return err
+	}
+	if err := w.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	w.written += int64(n + 1)
+	return nil
+}
+
+// shouldKeep reports whether a reassembled record passes -qlog-level. The
+// leading trace/header record has no "name" field at all (it carries
+// qlog_version/trace metadata instead), so it's always kept regardless of
+// level - without it the rest of the segment isn't valid qlog.
+func (w *rotatingQlogWriter) shouldKeep(record []byte) bool {
+	if w.level == "" || w.level == "all" {
+		return true
+	}
+	var ev struct {
+		Name *string `json:"name"`
+	}
+	if err := json.Unmarshal(record, &ev); err != nil {
+		return true // keep anything we can't parse rather than silently drop it
+	}
+	if ev.Name == nil {
+		return true // header/trace record, not a filterable event
+	}
+	return levelAllows(w.level, categoryOf(*ev.Name))
+}
+
+// rotate flushes and gzip-compresses the current segment, records it in the
+// manifest, and opens a fresh segment.
+func (w *rotatingQlogWriter) rotate() {
+	if w.f == nil {
+		return
+	}
+	_ = w.w.Flush()
+	name := w.f.Name()
+	_ = w.f.Close()
+
+	gzName := name + ".gz"
+	if err := gzipFile(name, gzName); err == nil {
+		_ = os.Remove(name)
+	} else {
+		gzName = name
+	}
+
+	w.manifest = append(w.manifest, manifestEntry{
+		File:       filepath.Base(gzName),
+		ConnID:     w.connID,
+		StartTime:  w.segStart,
+		EndTime:    time.Now(),
+		RecordSize: w.written,
+	})
+	w.writeManifest()
+
+	w.segIdx++
+	w.openSegment()
+}
+
+func (w *rotatingQlogWriter) writeManifest() {
+	b, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(w.manifestFp, b, 0644)
+}
+
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	if _, err := copyAll(gz, in); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+func copyAll(dst *gzip.Writer, src *os.File) (int64, error) {
+	buf := make([]byte, 64*1024)
+	var total int64
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return total, werr
+			}
+			total += int64(n)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return total, nil
+			}
+			return total, err
+		}
+	}
+}
+
+func (w *rotatingQlogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	if len(w.pending) > 0 {
+		// The underlying tracer should always terminate its last record
+		// with '\n', but flush defensively rather than drop trailing bytes.
+		_ = w.flushRecord()
+	}
+	_ = w.w.Flush()
+	err := w.f.Close()
+
+	w.manifest = append(w.manifest, manifestEntry{
+		File:       filepath.Base(w.f.Name()),
+		ConnID:     w.connID,
+		StartTime:  w.segStart,
+		EndTime:    time.Now(),
+		RecordSize: w.written,
+	})
+	w.writeManifest()
+	return err
+}
+
+// newQlogTracer builds the quic.Config.Tracer function, writing filtered,
+// rotating, gzip-compressed NDJSON qlog segments under dir instead of the
+// single unbounded file the client used to open per run.
+func newQlogTracer(dir, level string, rotateBytes int64) func(ctx context.Context, p logging.Perspective, connID quic.ConnectionID) *logging.ConnectionTracer {
+	return func(ctx context.Context, p logging.Perspective, connID quic.ConnectionID) *logging.ConnectionTracer {
+		sink := newRotatingQlogWriter(dir, level, connID.String(), rotateBytes)
+		return qlog.NewConnectionTracer(sink, p, connID)
+	}
+}
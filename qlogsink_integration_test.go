@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	quic "github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/logging"
+	"github.com/quic-go/quic-go/qlog"
+)
+
+// TestRotatingQlogWriterAgainstRealTracer drives quic-go's actual
+// qlog.NewConnectionTracer (not just categoryOf/levelAllows in
+// qlogsink_test.go) to pin down how it really calls Write: the header
+// record arrives as one Write containing its JSON and trailing '\n'
+// together, and each event arrives as a JSON-only Write followed by a
+// separate one-byte '\n' Write - quic-go never writes a record-separator
+// byte. If that framing ever changes, this test - not just a production run
+// - should catch it.
+func TestRotatingQlogWriterAgainstRealTracer(t *testing.T) {
+	dir := t.TempDir()
+	connID := quic.ConnectionIDFromBytes([]byte{1, 2, 3, 4})
+
+	sink := newRotatingQlogWriter(dir, "core", connID.String(), 0)
+	tracer := qlog.NewConnectionTracer(sink, logging.PerspectiveClient, connID)
+
+	tracer.StartedConnection(
+		&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234},
+		&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 4321},
+		connID, connID,
+	)
+	// recovery:metrics_updated should be filtered out at -qlog-level core.
+	tracer.UpdatedMetrics(&logging.RTTStats{}, 0, 0, 0)
+	tracer.Close()
+
+	segment := filepath.Join(dir, "client_"+connID.String()+".000.qlog")
+	f, err := os.Open(segment)
+	if err != nil {
+		t.Fatalf("open segment: %v", err)
+	}
+	defer f.Close()
+
+	var names []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var ev struct {
+			Name *string `json:"name"`
+		}
+		if err := json.Unmarshal(line, &ev); err != nil {
+			t.Fatalf("segment line is not a single JSON object (reassembly glommed records together): %v\nline: %s", err, line)
+		}
+		if ev.Name != nil {
+			names = append(names, *ev.Name)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("scanning segment: %v", err)
+	}
+
+	for _, name := range names {
+		if categoryOf(name) != qlogCore {
+			t.Errorf("segment kept event %q, which -qlog-level core should have filtered out", name)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestCategoryOf(t *testing.T) {
+	cases := map[string]qlogCategory{
+		"transport:packet_sent":           qlogCore,
+		"connectivity:connection_started": qlogCore,
+		"security:key_updated":            qlogCore,
+		"recovery:packet_lost":            qlogRecovery,
+		"recovery:metrics_updated":        qlogRecovery,
+		"transport:stream_frame_created":  qlogCore, // "transport:" prefix wins over the "frame" substring
+		"quic:frame_parsed":               qlogCore,
+		"unknown:something":               qlogOther,
+		"unknown:a_frame_event":           qlogFrame,
+	}
+	for name, want := range cases {
+		if got := categoryOf(name); got != want {
+			t.Errorf("categoryOf(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestLevelAllows(t *testing.T) {
+	cases := []struct {
+		level string
+		cat   qlogCategory
+		want  bool
+	}{
+		{"core", qlogCore, true},
+		{"core", qlogRecovery, false},
+		{"core", qlogFrame, false},
+		{"recovery", qlogCore, true},
+		{"recovery", qlogRecovery, true},
+		{"recovery", qlogFrame, false},
+		{"frame", qlogCore, true},
+		{"frame", qlogRecovery, true},
+		{"frame", qlogFrame, true},
+		{"all", qlogOther, true},
+		{"", qlogOther, true},
+	}
+	for _, tc := range cases {
+		if got := levelAllows(tc.level, tc.cat); got != tc.want {
+			t.Errorf("levelAllows(%q, %v) = %v, want %v", tc.level, tc.cat, got, tc.want)
+		}
+	}
+}
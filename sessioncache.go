@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"crypto/tls"
+)
+
+// diskSessionCache is a tls.ClientSessionCache backed by a single gob file,
+// so a session ticket issued on one run can be handed to a later one -
+// across the two process invocations the interop runner uses for
+// resumption/zerortt, or across separate manual runs via -session-file.
+type diskSessionCache struct {
+	path string
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newDiskSessionCache(path string) *diskSessionCache {
+	c := &diskSessionCache{path: path, data: map[string][]byte{}}
+	if b, err := os.ReadFile(path); err == nil {
+		_ = gob.NewDecoder(bytes.NewReader(b)).Decode(&c.data)
+	}
+	return c
+}
+
+// cachedSession is the gob-encoded form of one tls.ClientSessionState: the
+// session ticket plus the opaque state blob crypto/tls uses to reconstruct
+// it (tls.SessionState.Bytes/tls.ParseSessionState).
+type cachedSession struct {
+	Ticket []byte
+	State  []byte
+}
+
+func (c *diskSessionCache) Get(sessionKey string) (*tls.ClientSessionState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	raw, ok := c.data[sessionKey]
+	if !ok {
+		return nil, false
+	}
+	var cached cachedSession
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&cached); err != nil {
+		return nil, false
+	}
+	state, err := tls.ParseSessionState(cached.State)
+	if err != nil {
+		return nil, false
+	}
+	cs, err := tls.NewResumptionState(cached.Ticket, state)
+	if err != nil {
+		return nil, false
+	}
+	return cs, true
+}
+
+func (c *diskSessionCache) Put(sessionKey string, cs *tls.ClientSessionState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cs == nil {
+		delete(c.data, sessionKey)
+		return
+	}
+	ticket, state, err := cs.ResumptionState()
+	if err != nil {
+		return
+	}
+	stateBytes, err := state.Bytes()
+	if err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(cachedSession{Ticket: ticket, State: stateBytes}); err != nil {
+		return
+	}
+	c.data[sessionKey] = buf.Bytes()
+}
+
+// save persists the cache to disk. Call it once the handshake (and any
+// post-handshake NewSessionTicket messages) has had a chance to populate it.
+func (c *diskSessionCache) save() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c.data); err != nil {
+		return
+	}
+	_ = os.MkdirAll(filepath.Dir(c.path), 0755)
+	_ = os.WriteFile(c.path, buf.Bytes(), 0644)
+}
+
+// hasTicket reports whether the cache already holds at least one session,
+// i.e. whether this run could plausibly attempt 0-RTT.
+func (c *diskSessionCache) hasTicket() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.data) > 0
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskSessionCacheSaveAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.bin")
+
+	c := newDiskSessionCache(path)
+	if c.hasTicket() {
+		t.Fatal("fresh cache should have no tickets")
+	}
+	c.data["example.com"] = []byte("opaque-session-blob")
+	c.save()
+
+	reloaded := newDiskSessionCache(path)
+	if !reloaded.hasTicket() {
+		t.Fatal("reloaded cache should report a ticket")
+	}
+	if got := string(reloaded.data["example.com"]); got != "opaque-session-blob" {
+		t.Errorf("reloaded.data[example.com] = %q, want %q", got, "opaque-session-blob")
+	}
+}
+
+func TestDiskSessionCacheMissingFileStartsEmpty(t *testing.T) {
+	c := newDiskSessionCache(filepath.Join(t.TempDir(), "does-not-exist.bin"))
+	if c.hasTicket() {
+		t.Fatal("cache backed by a nonexistent file should start empty")
+	}
+}
+
+func TestDiskSessionCachePutNilDeletes(t *testing.T) {
+	c := newDiskSessionCache(filepath.Join(t.TempDir(), "cache.bin"))
+	c.data["example.com"] = []byte("stale")
+	c.Put("example.com", nil)
+	if c.hasTicket() {
+		t.Fatal("Put with a nil session should remove the cache entry")
+	}
+}
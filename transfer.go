@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	neturl "net/url"
+
+	quic "github.com/quic-go/quic-go"
+
+	"github.com/EMESSARL/quic-client-linux/h3client"
+)
+
+// downloadURLs fetches each of urls over HTTP/3 and saves the response
+// bodies under dir, used by the interop transfer/multiconnect/resumption
+// test cases to turn downloaded URLs into files on disk.
+func downloadURLs(urls []string, dir string, tlsConf *tls.Config, quicC *quic.Config) error {
+	summary, err := h3client.Run(context.Background(), h3client.Config{
+		URLs:        urls,
+		Method:      "GET",
+		Concurrency: 1,
+		TLSConfig:   tlsConf,
+		QuicConfig:  quicC,
+		OutputDir:   dir,
+	})
+	if err != nil {
+		return err
+	}
+	h3client.PrintReport(summary)
+	if summary.Errors > 0 {
+		return fmt.Errorf("%d/%d downloads failed", summary.Errors, summary.Total)
+	}
+	return nil
+}
+
+// addrFromURL extracts the "host:port" QUIC dial target from a URL,
+// defaulting to port 443 when none is given.
+func addrFromURL(rawURL string) (string, error) {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return host + ":" + port, nil
+}
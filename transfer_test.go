@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestAddrFromURL(t *testing.T) {
+	cases := []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/a", "example.com:443"},
+		{"https://example.com:4433/a", "example.com:4433"},
+		{"https://127.0.0.1:4447/", "127.0.0.1:4447"},
+	}
+	for _, tc := range cases {
+		got, err := addrFromURL(tc.url)
+		if err != nil {
+			t.Fatalf("addrFromURL(%q) error: %v", tc.url, err)
+		}
+		if got != tc.want {
+			t.Errorf("addrFromURL(%q) = %q, want %q", tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestAddrFromURLInvalid(t *testing.T) {
+	if _, err := addrFromURL("://bad-url"); err == nil {
+		t.Fatal("expected an error for a malformed URL")
+	}
+}